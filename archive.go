@@ -0,0 +1,146 @@
+package customerimporter
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ImportReport carries the diagnostics of an import that Import/ImportStream
+// used to drop straight into the standard logger: how many rows were read and
+// skipped, and the per-row errors behind them. ImportZip and ImportDir reuse
+// the same struct, additionally tracking how many files were processed versus
+// skipped, since a single bad file there shouldn't abort the remaining ones.
+type ImportReport struct {
+	FilesProcessed int
+	FilesSkipped   int
+	RowsRead       int
+	RowsSkipped    int
+	LineErrors     []LineError
+	Errors         []error
+}
+
+// ImportZip reads every CSV file contained in the ZIP archive at path,
+// streaming each one through Import and merging the per-file domain counters
+// into a single sorted result. Non-CSV entries are skipped. A parse error on
+// one entry is recorded in the returned ImportReport rather than aborting the
+// whole import.
+func (ci *customerImporter) ImportZip(path string) ([]EmailGroup, ImportReport, error) {
+	zipReader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, ImportReport{}, fmt.Errorf("unable to open zip file: %v", err)
+	}
+	defer zipReader.Close()
+
+	merged := make(map[string]int)
+	report := ImportReport{}
+
+	for _, entry := range zipReader.File {
+		if entry.FileInfo().IsDir() || !isCsvFile(entry.Name) {
+			continue
+		}
+
+		entryReport, err := ci.importZipEntry(entry, merged)
+		report.RowsRead += entryReport.RowsRead
+		report.RowsSkipped += entryReport.RowsSkipped
+		report.LineErrors = append(report.LineErrors, entryReport.LineErrors...)
+		if err != nil {
+			report.FilesSkipped++
+			report.Errors = append(report.Errors, fmt.Errorf("%s: %v", entry.Name, err))
+			continue
+		}
+		report.FilesProcessed++
+	}
+
+	return ci.sortResults(merged), report, nil
+}
+
+func (ci *customerImporter) importZipEntry(entry *zip.File, merged map[string]int) (ImportReport, error) {
+	f, err := entry.Open()
+	if err != nil {
+		return ImportReport{}, err
+	}
+	defer f.Close()
+
+	decoded, err := ci.decodedReader(f)
+	if err != nil {
+		return ImportReport{}, err
+	}
+
+	groups, report, err := ci.Import(csv.NewReader(decoded))
+	if err != nil {
+		return report, err
+	}
+	mergeGroups(merged, groups)
+	return report, nil
+}
+
+// ImportDir walks the directory tree rooted at path and streams every CSV file
+// it finds through Import, merging the per-file domain counters into a single
+// sorted result. A parse error on one file is recorded in the returned
+// ImportReport rather than aborting the whole walk.
+func (ci *customerImporter) ImportDir(path string) ([]EmailGroup, ImportReport, error) {
+	merged := make(map[string]int)
+	report := ImportReport{}
+
+	err := filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !isCsvFile(info.Name()) {
+			return nil
+		}
+
+		fileReport, err := ci.importDirFile(filePath, merged)
+		report.RowsRead += fileReport.RowsRead
+		report.RowsSkipped += fileReport.RowsSkipped
+		report.LineErrors = append(report.LineErrors, fileReport.LineErrors...)
+		if err != nil {
+			report.FilesSkipped++
+			report.Errors = append(report.Errors, fmt.Errorf("%s: %v", filePath, err))
+			return nil
+		}
+		report.FilesProcessed++
+		return nil
+	})
+	if err != nil {
+		return nil, report, fmt.Errorf("unable to walk directory: %v", err)
+	}
+
+	return ci.sortResults(merged), report, nil
+}
+
+func (ci *customerImporter) importDirFile(path string, merged map[string]int) (ImportReport, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ImportReport{}, err
+	}
+	defer f.Close()
+
+	decoded, err := ci.decodedReader(f)
+	if err != nil {
+		return ImportReport{}, err
+	}
+
+	groups, report, err := ci.Import(csv.NewReader(decoded))
+	if err != nil {
+		return report, err
+	}
+	mergeGroups(merged, groups)
+	return report, nil
+}
+
+// mergeGroups folds groups into dst, summing counters for domains seen in more
+// than one file.
+func mergeGroups(dst map[string]int, groups []EmailGroup) {
+	for _, group := range groups {
+		dst[group.EmailDomain] += group.Counter
+	}
+}
+
+func isCsvFile(name string) bool {
+	return strings.EqualFold(filepath.Ext(name), ".csv")
+}
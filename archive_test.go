@@ -0,0 +1,119 @@
+package customerimporter
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestImportZip(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "customers.zip")
+	f, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatalf("unable to create zip file: %v", err)
+	}
+
+	zipWriter := zip.NewWriter(f)
+	files := map[string]string{
+		"good1.csv": "first_name,last_name,email,gender,ip_address\n" +
+			"Mildred,Hernandez,mhernandez0@github.io,Female,38.194.51.128\n",
+		"good2.csv": "first_name,last_name,email,gender,ip_address\n" +
+			"Norma,Allen,nallen8@github.io,Female,168.67.162.1\n" +
+			"Anna,Rivera,ariverag@whitehouse.gov,Female,105.158.80.2\n",
+		"bad.csv": "first_name,last_name,MAIL,gender,ip_address\n" +
+			"Dennis,Henry,dhenry2@cyberchimps.com,Male,155.75.186.217\n",
+		"README.txt": "this is not a csv file and must be skipped entirely\n",
+	}
+	for name, content := range files {
+		w, err := zipWriter.Create(name)
+		if err != nil {
+			t.Fatalf("unable to create zip entry %s: %v", name, err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("unable to write zip entry %s: %v", name, err)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatalf("unable to close zip writer: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("unable to close zip file: %v", err)
+	}
+
+	ci := New()
+	groups, report, err := ci.ImportZip(zipPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.FilesProcessed != 2 {
+		t.Errorf("expected FilesProcessed 2; got %d", report.FilesProcessed)
+	}
+	if report.FilesSkipped != 1 {
+		t.Errorf("expected FilesSkipped 1; got %d", report.FilesSkipped)
+	}
+	if len(report.Errors) != 1 {
+		t.Errorf("expected 1 collected error; got %d", len(report.Errors))
+	}
+
+	expected := map[string]int{"github.io": 2, "whitehouse.gov": 1}
+	if len(groups) != len(expected) {
+		t.Fatalf("expected %d domains; got %d", len(expected), len(groups))
+	}
+	for _, cg := range groups {
+		if want, ok := expected[cg.EmailDomain]; !ok || cg.Counter != want {
+			t.Errorf("unexpected domain/counter: %+v", cg)
+		}
+	}
+}
+
+func TestImportDir(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "nested")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatalf("unable to create nested dir: %v", err)
+	}
+
+	files := map[string]string{
+		filepath.Join(root, "good1.csv"): "first_name,last_name,email,gender,ip_address\n" +
+			"Mildred,Hernandez,mhernandez0@github.io,Female,38.194.51.128\n",
+		filepath.Join(sub, "good2.csv"): "first_name,last_name,email,gender,ip_address\n" +
+			"Norma,Allen,nallen8@github.io,Female,168.67.162.1\n" +
+			"Anna,Rivera,ariverag@whitehouse.gov,Female,105.158.80.2\n",
+		filepath.Join(sub, "bad.csv"): "first_name,last_name,MAIL,gender,ip_address\n" +
+			"Dennis,Henry,dhenry2@cyberchimps.com,Male,155.75.186.217\n",
+		filepath.Join(root, "README.txt"): "this is not a csv file and must be skipped entirely\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("unable to write %s: %v", path, err)
+		}
+	}
+
+	ci := New()
+	groups, report, err := ci.ImportDir(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.FilesProcessed != 2 {
+		t.Errorf("expected FilesProcessed 2; got %d", report.FilesProcessed)
+	}
+	if report.FilesSkipped != 1 {
+		t.Errorf("expected FilesSkipped 1; got %d", report.FilesSkipped)
+	}
+	if len(report.Errors) != 1 {
+		t.Errorf("expected 1 collected error; got %d", len(report.Errors))
+	}
+
+	expected := map[string]int{"github.io": 2, "whitehouse.gov": 1}
+	if len(groups) != len(expected) {
+		t.Fatalf("expected %d domains; got %d", len(expected), len(groups))
+	}
+	for _, cg := range groups {
+		if want, ok := expected[cg.EmailDomain]; !ok || cg.Counter != want {
+			t.Errorf("unexpected domain/counter: %+v", cg)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package customerimporter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// Supported values for SetEncoding.
+const (
+	EncodingUTF8        = "utf-8"
+	EncodingUTF16       = "utf-16"
+	EncodingWindows1252 = "windows-1252"
+	EncodingGBK         = "gbk"
+	EncodingShiftJIS    = "shift-jis"
+)
+
+// defaultEncoding is used when SetEncoding has not been called.
+const defaultEncoding = EncodingUTF8
+
+// decodedReader wraps r so bytes are transcoded to UTF-8 according to
+// ci.Encoding before they ever reach the csv.Reader. utf-8 and utf-16 both
+// go through unicode.BOMOverride, which detects and strips a leading
+// byte-order mark and picks the right UTF decoder on its own; a BOM-prefixed
+// header would otherwise fail to match defaultEmailColumnName.
+func (ci *customerImporter) decodedReader(r io.Reader) (io.Reader, error) {
+	switch strings.ToLower(ci.Encoding) {
+	case "", EncodingUTF8, EncodingUTF16:
+		return transform.NewReader(r, unicode.BOMOverride(unicode.UTF8.NewDecoder())), nil
+	case EncodingWindows1252:
+		return charmap.Windows1252.NewDecoder().Reader(r), nil
+	case EncodingGBK:
+		return simplifiedchinese.GBK.NewDecoder().Reader(r), nil
+	case EncodingShiftJIS:
+		return japanese.ShiftJIS.NewDecoder().Reader(r), nil
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q", ci.Encoding)
+	}
+}
@@ -0,0 +1,75 @@
+package customerimporter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+func TestImportFileUTF8BOM(t *testing.T) {
+	content := "\ufeff" + `first_name,last_name,email,gender,ip_address
+Mildred,Hernandez,mhernandez0@github.io,Female,38.194.51.128
+Norma,Allen,nallen8@cnet.com,Female,168.67.162.1
+`
+
+	csvPath := filepath.Join(t.TempDir(), "customers.csv")
+	if err := os.WriteFile(csvPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("unable to write test file: %v", err)
+	}
+
+	ci := New()
+	ci.SetCsvFileName(csvPath)
+
+	customers, _, err := ci.ImportFile()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]int{"github.io": 1, "cnet.com": 1}
+	if len(customers) != len(expected) {
+		t.Fatalf("expected %d domains; got %d", len(expected), len(customers))
+	}
+	for _, cg := range customers {
+		if want, ok := expected[cg.EmailDomain]; !ok || cg.Counter != want {
+			t.Errorf("unexpected domain/counter: %+v", cg)
+		}
+	}
+}
+
+func TestImportFileGBK(t *testing.T) {
+	content := `first_name,last_name,email,gender,ip_address
+王,芳,mhernandez0@github.io,Female,38.194.51.128
+李,娜,nallen8@cnet.com,Female,168.67.162.1
+`
+
+	encoded, err := simplifiedchinese.GBK.NewEncoder().String(content)
+	if err != nil {
+		t.Fatalf("unable to encode test fixture as GBK: %v", err)
+	}
+
+	csvPath := filepath.Join(t.TempDir(), "customers.csv")
+	if err := os.WriteFile(csvPath, []byte(encoded), 0o644); err != nil {
+		t.Fatalf("unable to write test file: %v", err)
+	}
+
+	ci := New()
+	ci.SetCsvFileName(csvPath)
+	ci.SetEncoding(EncodingGBK)
+
+	customers, _, err := ci.ImportFile()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]int{"github.io": 1, "cnet.com": 1}
+	if len(customers) != len(expected) {
+		t.Fatalf("expected %d domains; got %d", len(expected), len(customers))
+	}
+	for _, cg := range customers {
+		if want, ok := expected[cg.EmailDomain]; !ok || cg.Counter != want {
+			t.Errorf("unexpected domain/counter: %+v", cg)
+		}
+	}
+}
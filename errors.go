@@ -0,0 +1,42 @@
+package customerimporter
+
+// LineError describes a single row that failed to import, giving callers
+// enough context (the raw fields and the underlying error) to decide what to
+// do about it instead of just seeing it vanish into a log line.
+type LineError struct {
+	Line int
+	Raw  []string
+	Err  error
+}
+
+func (e LineError) Error() string {
+	return e.Err.Error()
+}
+
+// Action tells the pipeline how to proceed after an ErrorHandler has looked
+// at a LineError.
+type Action int
+
+const (
+	// ActionSkip drops the row. It is counted in ImportReport.RowsSkipped.
+	// This is the default behavior when no ErrorHandler is set.
+	ActionSkip Action = iota
+
+	// ActionAbort stops the import on a best-effort basis: workers stop
+	// pulling new batches and bail out of the row they're on as soon as
+	// cancellation is observed, but rows already past that check in other
+	// workers still get processed. RowsRead only counts rows of batches a
+	// worker finished, so after an abort it can be above or below the aborting
+	// row by a scheduler-dependent amount. Import/ImportStream return the
+	// row's error.
+	ActionAbort
+
+	// ActionReplace counts the row under a sentinel "invalid" domain instead
+	// of dropping it, so RowsRead and the sum of the returned counters still
+	// agree.
+	ActionReplace
+)
+
+// invalidDomainPlaceholder is the domain a row is filed under when its
+// ErrorHandler returns ActionReplace.
+const invalidDomainPlaceholder = "invalid"
@@ -0,0 +1,72 @@
+package customerimporter
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func TestErrorHandlerActions(t *testing.T) {
+	content := `first_name,last_name,email,gender,ip_address
+Mildred,Hernandez,mhernandez0@github.io,Female,38.194.51.128
+Bonnie,Ortiz,bortiz1@cyberchimps.com,Female,197.54.209.129
+Dennis,Henry,dhenry2,Male,155.75.186.217
+Justin,Hansen,jhansen3@360.cn,Male,251.166.224.119`
+
+	tt := []struct {
+		name            string
+		action          Action
+		expectedResults map[string]int
+		rowsSkipped     int
+		errMsg          string
+	}{
+		{
+			name:            "ActionSkip drops the row and counts it in RowsSkipped",
+			action:          ActionSkip,
+			expectedResults: map[string]int{"github.io": 1, "cyberchimps.com": 1, "360.cn": 1},
+			rowsSkipped:     1,
+		},
+		{
+			name:            "ActionReplace files the row under the invalid placeholder",
+			action:          ActionReplace,
+			expectedResults: map[string]int{"github.io": 1, "cyberchimps.com": 1, "360.cn": 1, invalidDomainPlaceholder: 1},
+			rowsSkipped:     0,
+		},
+		{
+			name:   "ActionAbort stops the import and returns the row's error",
+			action: ActionAbort,
+			errMsg: "aborted at line 4: invalid email format: mail: missing '@' or angle-addr",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ci := New()
+			ci.SetErrorHandler(func(LineError) Action { return tc.action })
+			reader := csv.NewReader(strings.NewReader(content))
+
+			customers, report, err := ci.Import(reader)
+
+			if len(report.LineErrors) != 1 {
+				t.Fatalf("expected 1 LineError; got %d", len(report.LineErrors))
+			}
+			if report.LineErrors[0].Line != 4 {
+				t.Errorf("expected LineError on line 4; got %d", report.LineErrors[0].Line)
+			}
+
+			if !checkError(tc.errMsg, err, t) {
+				if report.RowsSkipped != tc.rowsSkipped {
+					t.Errorf("expected RowsSkipped %d; got %d", tc.rowsSkipped, report.RowsSkipped)
+				}
+				if len(customers) != len(tc.expectedResults) {
+					t.Fatalf("expected %d domains; got %d", len(tc.expectedResults), len(customers))
+				}
+				for _, cg := range customers {
+					if want, ok := tc.expectedResults[cg.EmailDomain]; !ok || cg.Counter != want {
+						t.Errorf("unexpected domain/counter: %+v", cg)
+					}
+				}
+			}
+		})
+	}
+}
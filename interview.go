@@ -7,19 +7,33 @@ package customerimporter
 
 import (
 	"bufio"
+	"context"
 	"encoding/csv"
 	"fmt"
-	"io"
 	"log"
 	"os"
-	"sort"
-	"strings"
+	"runtime"
+	"unicode/utf8"
 )
 
 const defaultCsvFileName = "customers.csv"
 const defaultEmailColumnName = "email"
-const numExpectedEmailComponents = 2 // the 2 components are username and mail domain: username@domain.com
-const emailDomainIndex = 1
+
+// defaultMaxEmailFieldLength mirrors the 200-char cap common in bulk
+// subscriber importers, guarding processLine against pathological rows.
+const defaultMaxEmailFieldLength = 200
+
+// defaultWorkerCount is used when no explicit worker count has been configured. It
+// scales with the machine so the pipeline keeps up with large inputs without the
+// caller having to tune it by hand.
+var defaultWorkerCount = runtime.NumCPU()
+
+// EmailGroup represents an email domain together with the number of customers
+// that use it.
+type EmailGroup struct {
+	EmailDomain string
+	Counter     int
+}
 
 // CustomerImporter interface exposes the methods for importing emails
 type CustomerImporter interface {
@@ -30,23 +44,108 @@ type CustomerImporter interface {
 	// SetEmailColumnName overwrites the default email column name ('email')
 	SetEmailColumnName(columnName string)
 
-	// Import imports the emails from a csv.Reader passed as parameter, group them by email domain and return a list of email domains including a counter
-	Import(reader *csv.Reader) ([]EmailGroup, error)
+	// SetWorkerCount configures how many goroutines parse CSV rows concurrently.
+	// Values less than 1 are ignored and the current count (runtime.NumCPU() by
+	// default) is kept.
+	SetWorkerCount(n int)
+
+	// SetProgressCallback registers a function that is invoked with the running
+	// total of rows processed so far. It may be called concurrently from several
+	// worker goroutines and should return quickly.
+	SetProgressCallback(fn func(rowsProcessed int))
+
+	// SetEmailValidator overrides the default RFC-5322-based EmailValidator,
+	// e.g. to add MX lookups or a disposable-domain blocklist.
+	SetEmailValidator(v EmailValidator)
+
+	// SetMaxEmailFieldLength overrides the default cap on how many characters
+	// the email field of a row may contain. Values less than 1 disable the
+	// check.
+	SetMaxEmailFieldLength(n int)
+
+	// SetErrorHandler registers a function that decides what happens to a row
+	// that failed to import. If unset, every failing row is skipped (the
+	// previous log-and-continue behavior).
+	SetErrorHandler(fn func(LineError) Action)
+
+	// SetLogger makes Import/ImportStream additionally log each LineError to
+	// logger, preserving the logging behavior this package had before
+	// ImportReport existed.
+	SetLogger(logger *log.Logger)
+
+	// SetEncoding overwrites the default "utf-8" encoding used when reading a
+	// file by name (ImportFile, ImportZip, ImportDir). With "utf-8" or
+	// "utf-16" a leading UTF-8 or UTF-16 byte-order mark is stripped
+	// automatically; the other encodings do not strip one. See the Encoding*
+	// constants for the other supported values.
+	SetEncoding(enc string)
+
+	// SetSortOrder overwrites the default alphabetical-by-domain ordering of
+	// the results returned by Import, ImportFile and ImportStream.
+	SetSortOrder(order SortOrder)
+
+	// Import imports the emails from a csv.Reader passed as parameter, groups
+	// them by email domain and returns a list of email domains including a
+	// counter, alongside an ImportReport with row-level diagnostics.
+	Import(reader *csv.Reader) ([]EmailGroup, ImportReport, error)
 
-	// ImportFile imports the emails from the file 'customers.csv', group them by email domain and return a list of email domains including a counter
-	ImportFile() ([]EmailGroup, error)
+	// ImportFile imports the emails from the file 'customers.csv', groups them
+	// by email domain and returns a list of email domains including a
+	// counter, alongside an ImportReport with row-level diagnostics.
+	ImportFile() ([]EmailGroup, ImportReport, error)
+
+	// ImportStream is the streaming counterpart of Import: it fans rows out to
+	// a pool of workers and delivers results incrementally over a channel
+	// instead of buffering the whole file in memory, so memory stays flat
+	// regardless of input size. The report channel receives exactly one
+	// ImportReport once every row has been processed. All channels are closed
+	// once the import finishes; ctx cancellation stops the pipeline early and
+	// is reported on the error channel.
+	ImportStream(ctx context.Context, reader *csv.Reader) (<-chan EmailGroup, <-chan ImportReport, <-chan error)
+
+	// ImportZip reads every CSV file contained in the ZIP archive at path and
+	// merges their domain counters into a single sorted result. Per-entry parse
+	// errors are collected in the returned ImportReport instead of aborting the
+	// whole import.
+	ImportZip(path string) ([]EmailGroup, ImportReport, error)
+
+	// ImportDir walks the directory tree rooted at path and merges the domain
+	// counters of every CSV file it finds into a single sorted result. Per-file
+	// parse errors are collected in the returned ImportReport instead of
+	// aborting the whole walk.
+	ImportDir(path string) ([]EmailGroup, ImportReport, error)
+
+	// ImportTopK is like Import but only materializes the k domains with the
+	// highest counters, ordered according to order, using a bounded min-heap
+	// instead of sorting every unique domain. This turns the O(U log U) cost
+	// of a full sort into O(U log k), which matters once U (the number of
+	// unique domains) gets large.
+	ImportTopK(reader *csv.Reader, k int, order SortOrder) ([]EmailGroup, error)
 }
 
 type customerImporter struct {
-	CsvFileName     string
-	EmailColumnName string
+	CsvFileName         string
+	EmailColumnName     string
+	WorkerCount         int
+	ProgressFunc        func(rowsProcessed int)
+	EmailValidator      EmailValidator
+	MaxEmailFieldLength int
+	ErrorHandler        func(LineError) Action
+	Logger              *log.Logger
+	Encoding            string
+	SortOrder           SortOrder
 }
 
 // New creates a new instance of customerImporter returning the interface CustomerImporter
 func New() CustomerImporter {
 	return &customerImporter{
-		CsvFileName:     defaultCsvFileName,
-		EmailColumnName: defaultEmailColumnName,
+		CsvFileName:         defaultCsvFileName,
+		EmailColumnName:     defaultEmailColumnName,
+		WorkerCount:         defaultWorkerCount,
+		EmailValidator:      defaultEmailValidator{},
+		MaxEmailFieldLength: defaultMaxEmailFieldLength,
+		Encoding:            defaultEncoding,
+		SortOrder:           SortByDomainAsc,
 	}
 }
 
@@ -58,60 +157,78 @@ func (ci *customerImporter) SetEmailColumnName(columnName string) {
 	ci.EmailColumnName = columnName
 }
 
-func (ci *customerImporter) Import(reader *csv.Reader) ([]EmailGroup, error) {
-	// extract the column names from the first line of the file
-	emailColIndex, err := ci.parseHeader(reader)
-	if err != nil {
-		return nil, err
+func (ci *customerImporter) SetWorkerCount(n int) {
+	if n < 1 {
+		return
 	}
+	ci.WorkerCount = n
+}
 
-	// lineNumber is set to the value 2 because we already have processed the first line (header)
-	lineNumber := 2
-
-	// initialize a map with the email domain as key and a counter as value
-	custGrp := make(map[string]int)
-
-	// read the file by lines
-	for {
-		line, err := reader.Read()
-		if err == io.EOF {
-			// here we have arrived to the end of file (EOF)
-			break
-		} else if err != nil {
-			// here we found an error while reading from the file
-			return nil, err
-		}
-		err = ci.processLine(line, emailColIndex, custGrp, lineNumber)
-		if err != nil {
-			// errors found, log the problem and skip this line
-			log.Printf("skip line %d: %v", lineNumber, err)
-		}
-		lineNumber++
-	}
+func (ci *customerImporter) SetProgressCallback(fn func(rowsProcessed int)) {
+	ci.ProgressFunc = fn
+}
 
-	// sort the email domains list by name
-	results := ci.sortResults(custGrp)
+func (ci *customerImporter) SetEmailValidator(v EmailValidator) {
+	ci.EmailValidator = v
+}
 
-	return results, nil
+func (ci *customerImporter) SetMaxEmailFieldLength(n int) {
+	ci.MaxEmailFieldLength = n
 }
 
-func (ci *customerImporter) ImportFile() ([]EmailGroup, error) {
+func (ci *customerImporter) SetErrorHandler(fn func(LineError) Action) {
+	ci.ErrorHandler = fn
+}
+
+func (ci *customerImporter) SetLogger(logger *log.Logger) {
+	ci.Logger = logger
+}
+
+func (ci *customerImporter) SetEncoding(enc string) {
+	ci.Encoding = enc
+}
+
+func (ci *customerImporter) SetSortOrder(order SortOrder) {
+	ci.SortOrder = order
+}
+
+func (ci *customerImporter) Import(reader *csv.Reader) ([]EmailGroup, ImportReport, error) {
+	results, reports, errCh := ci.ImportStream(context.Background(), reader)
+
+	groups := make([]EmailGroup, 0)
+	for group := range results {
+		groups = append(groups, group)
+	}
+	report := <-reports
+	if err := <-errCh; err != nil {
+		return nil, report, err
+	}
+	return groups, report, nil
+}
+
+func (ci *customerImporter) ImportFile() ([]EmailGroup, ImportReport, error) {
 	// first open the file
 	csvFile, err := os.Open(ci.CsvFileName)
 	if err != nil {
-		return nil, fmt.Errorf("unable to open file: %v", err)
+		return nil, ImportReport{}, fmt.Errorf("unable to open file: %v", err)
 	}
 	defer csvFile.Close()
 
+	// transcode to UTF-8 (stripping any BOM) before the CSV reader ever sees the bytes
+	decoded, err := ci.decodedReader(csvFile)
+	if err != nil {
+		return nil, ImportReport{}, err
+	}
+
 	// then creates a reader to access the file content
-	reader := csv.NewReader(bufio.NewReader(csvFile))
+	reader := csv.NewReader(bufio.NewReader(decoded))
 
 	// finally call the Import function passing the Reader interface to retrieve a list of emails grouped by email domain
-	customers, err := ci.Import(reader)
+	customers, report, err := ci.Import(reader)
 	if err != nil {
-		return nil, err
+		return nil, report, err
 	}
-	return customers, nil
+	return customers, report, nil
 }
 
 // read the header (first line) to determine in which column is placed the email column
@@ -142,22 +259,14 @@ func (ci *customerImporter) parseHeader(reader *csv.Reader) (int, error) {
 }
 
 func (ci *customerImporter) sortResults(custGrp map[string]int) []EmailGroup {
-	// sort the elements alphabetically by email domain (it is, the key of the map)
-	results := []EmailGroup{}
-	keys := make([]string, 0, len(custGrp))
-	for k := range custGrp {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-
-	// build the response with the sorted elements
-	for _, domain := range keys {
+	results := make([]EmailGroup, 0, len(custGrp))
+	for domain, counter := range custGrp {
 		results = append(results, EmailGroup{
 			EmailDomain: domain,
-			Counter:     custGrp[domain],
+			Counter:     counter,
 		})
 	}
-	return results
+	return sortGroups(results, ci.SortOrder)
 }
 
 func (ci *customerImporter) processLine(line []string, emailColIndex int, custGrp map[string]int, lineNumber int) error {
@@ -166,17 +275,17 @@ func (ci *customerImporter) processLine(line []string, emailColIndex int, custGr
 		return fmt.Errorf("email field not found at line %d", lineNumber)
 	}
 
-	// extract the components (username and domain) from the email field
 	emailField := line[emailColIndex]
-	components := strings.Split(emailField, "@")
-
-	// check that is a valid email (containing an '@' symbol)
-	if len(components) == numExpectedEmailComponents {
-		mailDomain := strings.ToLower(components[emailDomainIndex])
+	if ci.MaxEmailFieldLength > 0 && utf8.RuneCountInString(emailField) > ci.MaxEmailFieldLength {
+		return fmt.Errorf("email field exceeds max length of %d characters", ci.MaxEmailFieldLength)
+	}
 
-		// update the map, if the element does not exists will be created
-		custGrp[mailDomain]++
-		return nil
+	mailDomain, err := ci.EmailValidator.Validate(emailField)
+	if err != nil {
+		return err
 	}
-	return fmt.Errorf("invalid email format")
+
+	// update the map, if the element does not exists will be created
+	custGrp[mailDomain]++
+	return nil
 }
@@ -66,7 +66,7 @@ func TestImport(t *testing.T) {
 			if !tc.readerIsNil {
 				reader = csv.NewReader(strings.NewReader(tc.csvFileContent))
 			}
-			customers, err := ci.Import(reader)
+			customers, _, err := ci.Import(reader)
 
 			if !checkError(tc.errMsg, err, t) {
 				if tc.expectedResults != nil {
@@ -115,7 +115,7 @@ func TestImportFile(t *testing.T) {
 				ci.SetCsvFileName(tc.csvFileName)
 			}
 
-			customers, err := ci.ImportFile()
+			customers, _, err := ci.ImportFile()
 
 			if !checkError(tc.errMsg, err, t) {
 				if tc.expectedNumResults != -1 {
@@ -0,0 +1,246 @@
+package customerimporter
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// batchSize is the number of CSV rows handed to a worker at a time. Batching
+// amortizes channel overhead while keeping the in-flight queue bounded, so
+// memory stays flat no matter how large the input file is.
+const batchSize = 500
+
+// lineBatch is a contiguous run of rows read from the CSV, tagged with the line
+// number of its first row so per-row errors can still be reported accurately.
+type lineBatch struct {
+	startLine int
+	lines     [][]string
+}
+
+// workerResult is what a worker goroutine hands to the reducer once it has
+// drained its share of batches: its local domain counters plus the row-level
+// diagnostics it accumulated along the way.
+type workerResult struct {
+	counts      map[string]int
+	rowsSkipped int
+	lineErrors  []LineError
+}
+
+// ImportStream reads rows from reader through a fan-out/fan-in pipeline: a
+// single reader goroutine produces bounded batches of rows, WorkerCount worker
+// goroutines parse and lowercase the email domain into their own local
+// counters (avoiding lock contention on the hot path), and a reducer goroutine
+// merges the per-worker shards before emitting the final, sorted groups on the
+// returned channel.
+//
+// Cancelling ctx stops the pipeline early; the resulting context error is
+// delivered on the error channel. The report channel receives exactly one
+// ImportReport once every row has been processed. All three channels are
+// closed once the import finishes, successfully or not.
+func (ci *customerImporter) ImportStream(ctx context.Context, reader *csv.Reader) (<-chan EmailGroup, <-chan ImportReport, <-chan error) {
+	results := make(chan EmailGroup)
+	reports := make(chan ImportReport, 1)
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(results)
+		defer close(reports)
+		defer close(errCh)
+
+		merged, report, err := ci.runPipeline(ctx, reader)
+		reports <- report
+		if err != nil {
+			errCh <- err
+			return
+		}
+
+		for _, group := range ci.sortResults(merged) {
+			select {
+			case results <- group:
+			case <-ctx.Done():
+				errCh <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return results, reports, errCh
+}
+
+// runPipeline performs the fan-out/fan-in counting pass described by
+// ImportStream and blocks until it's done, returning the merged (unsorted)
+// domain counters and the aggregated ImportReport. It is the shared core
+// behind ImportStream and ImportTopK.
+func (ci *customerImporter) runPipeline(ctx context.Context, reader *csv.Reader) (map[string]int, ImportReport, error) {
+	emailColIndex, err := ci.parseHeader(reader)
+	if err != nil {
+		return nil, ImportReport{}, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workerCount := ci.WorkerCount
+	if workerCount < 1 {
+		workerCount = defaultWorkerCount
+	}
+
+	batches := make(chan lineBatch, workerCount*2)
+	shards := make(chan workerResult, workerCount)
+
+	var readErr error
+	var readErrOnce sync.Once
+	setReadErr := func(err error) {
+		readErrOnce.Do(func() { readErr = err })
+	}
+
+	// reader goroutine: turns CSV rows into bounded batches
+	go func() {
+		defer close(batches)
+
+		lineNumber := 2 // line 1 is the header, already consumed by parseHeader
+		batch := lineBatch{startLine: lineNumber}
+		for {
+			select {
+			case <-ctx.Done():
+				setReadErr(ctx.Err())
+				return
+			default:
+			}
+
+			line, err := reader.Read()
+			if err == io.EOF {
+				if len(batch.lines) > 0 {
+					select {
+					case batches <- batch:
+					case <-ctx.Done():
+						setReadErr(ctx.Err())
+					}
+				}
+				return
+			} else if err != nil {
+				setReadErr(err)
+				return
+			}
+
+			batch.lines = append(batch.lines, line)
+			lineNumber++
+			if len(batch.lines) >= batchSize {
+				select {
+				case batches <- batch:
+				case <-ctx.Done():
+					setReadErr(ctx.Err())
+					return
+				}
+				batch = lineBatch{startLine: lineNumber}
+			}
+		}
+	}()
+
+	// worker pool: each worker keeps its own local map to avoid lock
+	// contention, merging happens once in the reducer below
+	var wg sync.WaitGroup
+	var rowsProcessed int64
+	for i := 0; i < workerCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := workerResult{counts: make(map[string]int)}
+
+		batchLoop:
+			for {
+				// check cancellation before pulling another batch so a
+				// worker that's caught up doesn't keep draining the queue
+				// after abort has already been signaled
+				select {
+				case <-ctx.Done():
+					break batchLoop
+				default:
+				}
+
+				select {
+				case <-ctx.Done():
+					break batchLoop
+				case batch, ok := <-batches:
+					if !ok {
+						break batchLoop
+					}
+					lineNumber := batch.startLine
+					for _, row := range batch.lines {
+						select {
+						case <-ctx.Done():
+							break batchLoop
+						default:
+						}
+						if err := ci.processLine(row, emailColIndex, result.counts, lineNumber); err != nil {
+							if ci.handleLineError(LineError{Line: lineNumber, Raw: row, Err: err}, &result, cancel, setReadErr) {
+								break batchLoop
+							}
+						}
+						lineNumber++
+					}
+					processed := atomic.AddInt64(&rowsProcessed, int64(len(batch.lines)))
+					if ci.ProgressFunc != nil {
+						ci.ProgressFunc(int(processed))
+					}
+				}
+			}
+
+			shards <- result
+		}()
+	}
+	wg.Wait()
+	close(shards)
+
+	// the reader goroutine may have hit EOF before the cancellation, so make
+	// sure a cancelled run still surfaces the context error
+	if err := ctx.Err(); err != nil {
+		setReadErr(err)
+	}
+
+	merged := make(map[string]int)
+	report := ImportReport{}
+	for shard := range shards {
+		for domain, count := range shard.counts {
+			merged[domain] += count
+		}
+		report.RowsSkipped += shard.rowsSkipped
+		report.LineErrors = append(report.LineErrors, shard.lineErrors...)
+	}
+	report.RowsRead = int(atomic.LoadInt64(&rowsProcessed))
+
+	return merged, report, readErr
+}
+
+// handleLineError applies the configured ErrorHandler (ActionSkip by default)
+// to a failing row, updates result accordingly and, for ActionAbort, triggers
+// pipeline cancellation. It returns true if the caller's batch loop should
+// stop processing further rows.
+func (ci *customerImporter) handleLineError(lineErr LineError, result *workerResult, cancel context.CancelFunc, setReadErr func(error)) bool {
+	if ci.Logger != nil {
+		ci.Logger.Printf("skip line %d: %v", lineErr.Line, lineErr.Err)
+	}
+
+	action := ActionSkip
+	if ci.ErrorHandler != nil {
+		action = ci.ErrorHandler(lineErr)
+	}
+
+	result.lineErrors = append(result.lineErrors, lineErr)
+
+	switch action {
+	case ActionAbort:
+		setReadErr(fmt.Errorf("aborted at line %d: %v", lineErr.Line, lineErr.Err))
+		cancel()
+		return true
+	case ActionReplace:
+		result.counts[invalidDomainPlaceholder]++
+	default:
+		result.rowsSkipped++
+	}
+	return false
+}
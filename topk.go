@@ -0,0 +1,112 @@
+package customerimporter
+
+import (
+	"container/heap"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"sort"
+)
+
+// SortOrder controls how the groups returned by Import, ImportFile,
+// ImportStream and ImportTopK are ordered.
+type SortOrder int
+
+const (
+	// SortByDomainAsc orders groups alphabetically by email domain. This is
+	// the default, and the order Import always used before SortOrder existed.
+	SortByDomainAsc SortOrder = iota
+
+	// SortByCountDesc orders groups by counter, highest first.
+	SortByCountDesc
+
+	// SortByCountAsc orders groups by counter, lowest first.
+	SortByCountAsc
+)
+
+// sortGroups orders groups in place according to order, breaking ties by
+// domain name ascending so the output is stable regardless of map iteration
+// order.
+func sortGroups(groups []EmailGroup, order SortOrder) []EmailGroup {
+	switch order {
+	case SortByCountDesc:
+		sort.Slice(groups, func(i, j int) bool {
+			if groups[i].Counter != groups[j].Counter {
+				return groups[i].Counter > groups[j].Counter
+			}
+			return groups[i].EmailDomain < groups[j].EmailDomain
+		})
+	case SortByCountAsc:
+		sort.Slice(groups, func(i, j int) bool {
+			if groups[i].Counter != groups[j].Counter {
+				return groups[i].Counter < groups[j].Counter
+			}
+			return groups[i].EmailDomain < groups[j].EmailDomain
+		})
+	default:
+		sort.Slice(groups, func(i, j int) bool {
+			return groups[i].EmailDomain < groups[j].EmailDomain
+		})
+	}
+	return groups
+}
+
+// emailGroupHeap is a min-heap of EmailGroup keyed on Counter, with ties
+// broken by domain name so the group evicted first when the heap exceeds its
+// capacity is deterministic.
+type emailGroupHeap []EmailGroup
+
+func (h emailGroupHeap) Len() int { return len(h) }
+
+func (h emailGroupHeap) Less(i, j int) bool {
+	if h[i].Counter != h[j].Counter {
+		return h[i].Counter < h[j].Counter
+	}
+	return h[i].EmailDomain > h[j].EmailDomain
+}
+
+func (h emailGroupHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *emailGroupHeap) Push(x interface{}) {
+	*h = append(*h, x.(EmailGroup))
+}
+
+func (h *emailGroupHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// ImportTopK is like Import but only materializes the k domains with the
+// highest counters, then orders that subset according to order. It maintains
+// a min-heap of size k while scanning the unique domains once, turning the
+// O(U log U) cost of a full sort into O(U log k) — the difference matters
+// once U (the number of unique domains) gets large and the caller only wants
+// a handful of them.
+func (ci *customerImporter) ImportTopK(reader *csv.Reader, k int, order SortOrder) ([]EmailGroup, error) {
+	if k < 1 {
+		return nil, fmt.Errorf("k must be greater than 0")
+	}
+
+	merged, _, err := ci.runPipeline(context.Background(), reader)
+	if err != nil {
+		return nil, err
+	}
+
+	h := make(emailGroupHeap, 0, k+1)
+	for domain, counter := range merged {
+		heap.Push(&h, EmailGroup{EmailDomain: domain, Counter: counter})
+		if h.Len() > k {
+			heap.Pop(&h)
+		}
+	}
+
+	results := make([]EmailGroup, h.Len())
+	for i := len(results) - 1; i >= 0; i-- {
+		results[i] = heap.Pop(&h).(EmailGroup)
+	}
+
+	return sortGroups(results, order), nil
+}
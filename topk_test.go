@@ -0,0 +1,72 @@
+package customerimporter
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func TestImportTopK(t *testing.T) {
+	content := `first_name,last_name,email,gender,ip_address
+					Mildred,Hernandez,mhernandez0@github.io,Female,38.194.51.128
+					Norma,Allen,nallen8@cnet.com,Female,168.67.162.1
+					Anna,Rivera,ariverag@whitehouse.gov,Female,105.158.80.2
+					Lori,Elliott,lelliotth@github.io,Female,160.108.154.74
+					Wanda,Lewis,wlewisk@woothemes.com,Female,25.32.100.250
+					Robert,Hunter,rhunterp@google.fr,Male,130.35.232.64
+					Gregory,Ryan,gryanq@google.fr,Male,188.242.255.152
+					Andrew,Morgan,amorganr@google.fr,Male,3.184.160.117
+					Peter,Day,pdays@woothemes.com,Male,0.24.246.12`
+
+	tt := []struct {
+		name     string
+		k        int
+		order    SortOrder
+		expected []EmailGroup
+		errMsg   string
+	}{
+		{
+			name:  "top 2 listed by count desc",
+			k:     2,
+			order: SortByCountDesc,
+			expected: []EmailGroup{
+				{EmailDomain: "google.fr", Counter: 3},
+				{EmailDomain: "github.io", Counter: 2},
+			},
+		},
+		{
+			name:  "top 2 listed by count asc",
+			k:     2,
+			order: SortByCountAsc,
+			expected: []EmailGroup{
+				{EmailDomain: "github.io", Counter: 2},
+				{EmailDomain: "google.fr", Counter: 3},
+			},
+		},
+		{
+			name:   "invalid k",
+			k:      0,
+			order:  SortByCountDesc,
+			errMsg: "k must be greater than 0",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ci := New()
+			reader := csv.NewReader(strings.NewReader(content))
+
+			groups, err := ci.ImportTopK(reader, tc.k, tc.order)
+			if !checkError(tc.errMsg, err, t) {
+				if len(groups) != len(tc.expected) {
+					t.Fatalf("expected %d groups; got %d", len(tc.expected), len(groups))
+				}
+				for i, want := range tc.expected {
+					if groups[i] != want {
+						t.Errorf("expected group %d to be %+v; got %+v", i, want, groups[i])
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package customerimporter
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// EmailValidator checks that an email field is well-formed and returns its
+// normalized domain. Implementations can plug in stricter or laxer policies
+// (MX lookups, disposable-domain blocklists, ...) via SetEmailValidator.
+type EmailValidator interface {
+	// Validate parses emailField and returns its normalized (lowercased,
+	// IDN-normalized) domain, or an error if the field is not a valid email
+	// address.
+	Validate(emailField string) (domain string, err error)
+}
+
+// defaultEmailValidator is the EmailValidator used unless a caller overrides
+// it with SetEmailValidator. It relies on net/mail for RFC 5322 parsing, which
+// rejects the common false positives (e.g. "foo@", "@bar.com") that a bare
+// strings.Split on "@" lets through.
+type defaultEmailValidator struct{}
+
+func (defaultEmailValidator) Validate(emailField string) (string, error) {
+	addr, err := mail.ParseAddress(emailField)
+	if err != nil {
+		return "", fmt.Errorf("invalid email format: %v", err)
+	}
+
+	at := strings.LastIndex(addr.Address, "@")
+	if at < 0 || at == len(addr.Address)-1 {
+		return "", fmt.Errorf("invalid email format")
+	}
+
+	domain := strings.ToLower(addr.Address[at+1:])
+	if domain == "" || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") {
+		return "", fmt.Errorf("invalid email format: malformed domain %q", domain)
+	}
+
+	// normalize IDN domains to their ASCII (punycode) form so visually
+	// distinct-but-equivalent domains are counted together
+	normalized, err := idna.Lookup.ToASCII(domain)
+	if err != nil {
+		return "", fmt.Errorf("invalid email format: malformed domain %q: %v", domain, err)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,54 @@
+package customerimporter
+
+import "testing"
+
+func TestDefaultEmailValidatorValidate(t *testing.T) {
+	tt := []struct {
+		name       string
+		email      string
+		wantDomain string
+		errMsg     string
+	}{
+		{
+			name:   "missing domain",
+			email:  "foo@",
+			errMsg: "invalid email format: mail: no angle-addr",
+		},
+		{
+			name:   "missing local part",
+			email:  "@bar.com",
+			errMsg: "invalid email format: mail: no angle-addr",
+		},
+		{
+			name:   "IP-literal domain",
+			email:  "user@[192.168.1.1]",
+			errMsg: "invalid email format: mail: no angle-addr",
+		},
+		{
+			name:       "quoted local part with embedded at sign",
+			email:      `"foo@bar"@example.com`,
+			wantDomain: "example.com",
+		},
+		{
+			name:       "ordinary address",
+			email:      "jdoe@example.com",
+			wantDomain: "example.com",
+		},
+		{
+			name:       "IDN domain is normalized to punycode",
+			email:      "user@пример.рф",
+			wantDomain: "xn--e1afmkfd.xn--p1ai",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			domain, err := (defaultEmailValidator{}).Validate(tc.email)
+			if !checkError(tc.errMsg, err, t) {
+				if domain != tc.wantDomain {
+					t.Errorf("expected domain %q; got %q", tc.wantDomain, domain)
+				}
+			}
+		})
+	}
+}